@@ -0,0 +1,416 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"howett.net/plist"
+)
+
+// BrowserSource описывает источник закладок одного браузера: как найти
+// файл/профиль по умолчанию и как превратить его содержимое в дерево
+// BookmarkItem. Все реализации отдают результат в унифицированном виде,
+// так что дальше по конвейеру (extractBookmarksTagged -> saveBookmarksToDB)
+// не важно, откуда взялись закладки.
+type BrowserSource interface {
+	// Name возвращает короткое имя источника, используемое во флаге --browser.
+	Name() string
+	// ResolvePath возвращает путь к файлу/профилю закладок для заданного
+	// имени профиля (пустая строка означает профиль по умолчанию).
+	ResolvePath(profile string) (string, error)
+	// Load читает файл по пути path и возвращает дерево закладок.
+	Load(path string) ([]BookmarkItem, error)
+}
+
+// browserSources содержит все поддерживаемые источники, ключ — имя,
+// принимаемое флагом --browser.
+var browserSources = map[string]BrowserSource{
+	"chrome":   chromiumSource{browser: "chrome"},
+	"chromium": chromiumSource{browser: "chromium"},
+	"edge":     chromiumSource{browser: "edge"},
+	"brave":    chromiumSource{browser: "brave"},
+	"vivaldi":  chromiumSource{browser: "vivaldi"},
+	"firefox":  firefoxSource{},
+	"safari":   safariSource{},
+}
+
+// getBrowserSource возвращает BrowserSource по имени из флага --browser.
+func getBrowserSource(name string) (BrowserSource, error) {
+	source, ok := browserSources[name]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный браузер %q (доступны: chrome, chromium, edge, brave, vivaldi, firefox, safari)", name)
+	}
+	return source, nil
+}
+
+// chromiumSource — общая реализация для всех браузеров на движке Chromium
+// (Chrome, Chromium, Edge, Brave, Vivaldi), которые используют одинаковый
+// формат JSON-файла закладок, но хранят его в разных каталогах.
+type chromiumSource struct {
+	browser string
+}
+
+func (c chromiumSource) Name() string { return c.browser }
+
+// chromiumDirNames задаёт имя каталога профиля браузера на каждой ОС;
+// сам файл закладок всегда лежит внутри профиля как "Bookmarks".
+var chromiumDirNames = map[string]struct {
+	windows []string
+	darwin  []string
+	linux   []string
+}{
+	"chrome":   {[]string{"Google", "Chrome", "User Data"}, []string{"Google", "Chrome"}, []string{"google-chrome"}},
+	"chromium": {[]string{"Chromium", "User Data"}, []string{"Chromium"}, []string{"chromium"}},
+	"edge":     {[]string{"Microsoft", "Edge", "User Data"}, []string{"Microsoft Edge"}, []string{"microsoft-edge"}},
+	"brave":    {[]string{"BraveSoftware", "Brave-Browser", "User Data"}, []string{"BraveSoftware", "Brave-Browser"}, []string{"BraveSoftware/Brave-Browser"}},
+	"vivaldi":  {[]string{"Vivaldi", "User Data"}, []string{"Vivaldi"}, []string{"vivaldi"}},
+}
+
+func (c chromiumSource) ResolvePath(profile string) (string, error) {
+	if profile == "" {
+		profile = "Default"
+	}
+
+	dirs, ok := chromiumDirNames[c.browser]
+	if !ok {
+		return "", fmt.Errorf("нет известных путей профиля для браузера %q", c.browser)
+	}
+
+	var base string
+	switch runtime.GOOS {
+	case "windows":
+		localAppData := os.Getenv("LOCALAPPDATA")
+		if localAppData == "" {
+			return "", fmt.Errorf("не удалось получить переменную окружения LOCALAPPDATA")
+		}
+		base = filepath.Join(append([]string{localAppData}, dirs.windows...)...)
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("не удалось получить домашний каталог: %w", err)
+		}
+		base = filepath.Join(append([]string{home, "Library", "Application Support"}, dirs.darwin...)...)
+	default: // linux и прочие unix-подобные
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("не удалось получить домашний каталог: %w", err)
+		}
+		base = filepath.Join(append([]string{home, ".config"}, dirs.linux...)...)
+	}
+
+	path := filepath.Join(base, profile, "Bookmarks")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("файл закладок %s не найден по пути: %s", c.browser, path)
+	}
+
+	return path, nil
+}
+
+func (c chromiumSource) Load(path string) ([]BookmarkItem, error) {
+	bookmarks, err := parseBookmarks(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []BookmarkItem
+	items = append(items, bookmarks.Roots.BookmarkBar.Children...)
+	items = append(items, bookmarks.Roots.Other.Children...)
+	items = append(items, bookmarks.Roots.Synced.Children...)
+
+	return items, nil
+}
+
+// firefoxSource читает закладки из places.sqlite Firefox.
+type firefoxSource struct{}
+
+func (firefoxSource) Name() string { return "firefox" }
+
+func (firefoxSource) ResolvePath(profile string) (string, error) {
+	var profilesRoot string
+	switch runtime.GOOS {
+	case "windows":
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", fmt.Errorf("не удалось получить переменную окружения APPDATA")
+		}
+		profilesRoot = filepath.Join(appData, "Mozilla", "Firefox", "Profiles")
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("не удалось получить домашний каталог: %w", err)
+		}
+		profilesRoot = filepath.Join(home, "Library", "Application Support", "Firefox", "Profiles")
+	default:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("не удалось получить домашний каталог: %w", err)
+		}
+		profilesRoot = filepath.Join(home, ".mozilla", "firefox")
+	}
+
+	if profile != "" {
+		path := filepath.Join(profilesRoot, profile, "places.sqlite")
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("файл places.sqlite не найден по пути: %s", path)
+		}
+		return path, nil
+	}
+
+	// Без явного имени профиля берём первый (в порядке os.ReadDir) каталог,
+	// в котором есть places.sqlite; суффикс имени каталога не проверяется.
+	entries, err := os.ReadDir(profilesRoot)
+	if err != nil {
+		return "", fmt.Errorf("ошибка чтения каталога профилей Firefox: %w", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path := filepath.Join(profilesRoot, e.Name(), "places.sqlite")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("не найден профиль Firefox с places.sqlite в %s", profilesRoot)
+}
+
+// Load копирует places.sqlite вместе с -wal/-shm во временный каталог
+// (Firefox может держать на живой базе WAL-блокировку, поэтому открывать
+// оригинал напрямую небезопасно), затем открывает копию в режиме
+// "только чтение" и восстанавливает для каждой закладки путь папки
+// (FolderPath), обходя родителей в moz_bookmarks снизу вверх. Закладки
+// возвращаются плоским списком, без элементов-папок.
+func (f firefoxSource) Load(path string) ([]BookmarkItem, error) {
+	tmpPath, cleanup, err := copyPlacesDB(path)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	db, err := sql.Open("sqlite", "file:"+tmpPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия копии places.sqlite: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+		SELECT b.id, b.parent, b.title, b.dateAdded, p.url
+		FROM moz_bookmarks b
+		LEFT JOIN moz_places p ON b.fk = p.id
+		WHERE b.type = 1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса moz_bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	type row struct {
+		id, parent int64
+		title      string
+		dateAdded  int64
+		url        string
+	}
+	var flat []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.parent, &r.title, &r.dateAdded, &r.url); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки moz_bookmarks: %w", err)
+		}
+		flat = append(flat, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по moz_bookmarks: %w", err)
+	}
+
+	folderNames, err := f.loadFolderNames(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []BookmarkItem
+	for _, r := range flat {
+		items = append(items, BookmarkItem{
+			Type:       "url",
+			Name:       r.title,
+			URL:        r.url,
+			DateAdded:  fmt.Sprintf("%d", r.dateAdded),
+			FolderPath: resolveFirefoxFolderPath(r.parent, folderNames),
+		})
+	}
+
+	return items, nil
+}
+
+// loadFolderNames загружает id->(parent, title) для всех папок
+// (moz_bookmarks.type = 2), чтобы можно было восстановить FolderPath.
+func (firefoxSource) loadFolderNames(db *sql.DB) (map[int64]struct {
+	parent int64
+	title  string
+}, error) {
+	rows, err := db.Query("SELECT id, parent, title FROM moz_bookmarks WHERE type = 2")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса папок moz_bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	folders := map[int64]struct {
+		parent int64
+		title  string
+	}{}
+	for rows.Next() {
+		var id, parent int64
+		var title string
+		if err := rows.Scan(&id, &parent, &title); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования папки: %w", err)
+		}
+		folders[id] = struct {
+			parent int64
+			title  string
+		}{parent, title}
+	}
+
+	return folders, rows.Err()
+}
+
+func resolveFirefoxFolderPath(parent int64, folders map[int64]struct {
+	parent int64
+	title  string
+}) string {
+	var parts []string
+	for {
+		f, ok := folders[parent]
+		if !ok || f.title == "" {
+			break
+		}
+		parts = append([]string{f.title}, parts...)
+		parent = f.parent
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	path := parts[0]
+	for _, p := range parts[1:] {
+		path += "/" + p
+	}
+	return path
+}
+
+// copyPlacesDB копирует places.sqlite и сопутствующие -wal/-shm файлы во
+// временный каталог, возвращая путь к копии основного файла и функцию
+// очистки временного каталога.
+func copyPlacesDB(path string) (string, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "bookmark-converter-firefox-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("ошибка создания временного каталога: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	dst := filepath.Join(tmpDir, "places.sqlite")
+	if err := copyFile(path, dst); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	for _, suffix := range []string{"-wal", "-shm"} {
+		src := path + suffix
+		if _, err := os.Stat(src); err == nil {
+			if err := copyFile(src, dst+suffix); err != nil {
+				cleanup()
+				return "", nil, err
+			}
+		}
+	}
+
+	return dst, cleanup, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("ошибка создания %s: %w", dst, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("ошибка копирования %s в %s: %w", src, dst, err)
+	}
+
+	return nil
+}
+
+// safariSource читает закладки из бинарного plist-файла Safari.
+type safariSource struct{}
+
+func (safariSource) Name() string { return "safari" }
+
+func (safariSource) ResolvePath(profile string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("не удалось получить домашний каталог: %w", err)
+	}
+
+	path := filepath.Join(home, "Library", "Safari", "Bookmarks.plist")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("файл закладок Safari не найден по пути: %s", path)
+	}
+
+	return path, nil
+}
+
+// safariPlist описывает интересующую нас часть структуры Bookmarks.plist:
+// корневой список папок/закладок лежит в ключе "Children".
+type safariPlist struct {
+	Title    string        `plist:"Title"`
+	WLType   string        `plist:"WebBookmarkType"`
+	URL      string        `plist:"URLString"`
+	Children []safariPlist `plist:"Children"`
+}
+
+func (s safariSource) Load(path string) ([]BookmarkItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения Bookmarks.plist: %w", err)
+	}
+
+	var root safariPlist
+	if _, err := plist.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("ошибка разбора бинарного plist: %w", err)
+	}
+
+	return safariChildrenToItems(root.Children), nil
+}
+
+func safariChildrenToItems(children []safariPlist) []BookmarkItem {
+	var items []BookmarkItem
+
+	for _, c := range children {
+		switch c.WLType {
+		case "WebBookmarkTypeLeaf":
+			items = append(items, BookmarkItem{
+				Type: "url",
+				Name: c.Title,
+				URL:  c.URL,
+			})
+		case "WebBookmarkTypeList":
+			items = append(items, BookmarkItem{
+				Type:     "folder",
+				Name:     c.Title,
+				Children: safariChildrenToItems(c.Children),
+			})
+		}
+	}
+
+	return items
+}
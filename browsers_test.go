@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+// TestGetBrowserSource проверяет реестр известных источников и ошибку для
+// неизвестного имени браузера.
+func TestGetBrowserSource(t *testing.T) {
+	for _, name := range []string{"chrome", "chromium", "edge", "brave", "vivaldi", "firefox", "safari"} {
+		source, err := getBrowserSource(name)
+		if err != nil {
+			t.Errorf("getBrowserSource(%q) вернул ошибку: %v", name, err)
+			continue
+		}
+		if source.Name() != name {
+			t.Errorf("Name() = %q, ожидалось %q", source.Name(), name)
+		}
+	}
+
+	if _, err := getBrowserSource("unknown-browser"); err == nil {
+		t.Error("ожидалась ошибка для неизвестного браузера, получен nil")
+	}
+}
+
+// TestResolveFirefoxFolderPath проверяет восстановление пути папки по
+// дереву родителей moz_bookmarks.
+func TestResolveFirefoxFolderPath(t *testing.T) {
+	folders := map[int64]struct {
+		parent int64
+		title  string
+	}{
+		1: {parent: 0, title: "Work"},
+		2: {parent: 1, title: "Projects"},
+	}
+
+	got := resolveFirefoxFolderPath(2, folders)
+	if got != "Work/Projects" {
+		t.Errorf("неверный FolderPath. Получено: %q, ожидалось: %q", got, "Work/Projects")
+	}
+
+	if got := resolveFirefoxFolderPath(0, folders); got != "" {
+		t.Errorf("ожидался пустой FolderPath для корня, получено: %q", got)
+	}
+}
+
+// TestSafariChildrenToItems проверяет преобразование разобранного plist в
+// дерево BookmarkItem с учётом типов WebBookmarkType*.
+func TestSafariChildrenToItems(t *testing.T) {
+	children := []safariPlist{
+		{WLType: "WebBookmarkTypeLeaf", Title: "Example", URL: "https://example.com"},
+		{
+			WLType: "WebBookmarkTypeList",
+			Title:  "Folder",
+			Children: []safariPlist{
+				{WLType: "WebBookmarkTypeLeaf", Title: "Nested", URL: "https://nested.example.com"},
+			},
+		},
+	}
+
+	items := safariChildrenToItems(children)
+	if len(items) != 2 {
+		t.Fatalf("ожидалось 2 элемента верхнего уровня, получено %d", len(items))
+	}
+	if items[0].Type != "url" || items[0].URL != "https://example.com" {
+		t.Errorf("неверная закладка верхнего уровня: %+v", items[0])
+	}
+	if items[1].Type != "folder" || len(items[1].Children) != 1 {
+		t.Fatalf("неверная папка: %+v", items[1])
+	}
+	if items[1].Children[0].URL != "https://nested.example.com" {
+		t.Errorf("закладка внутри Folder не найдена: %+v", items[1].Children[0])
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/gocarina/gocsv"
+)
+
+// Exporter превращает список закладок в конкретный формат файла. Все
+// реализации пишут напрямую в w; подкоманда `export` выбирает реализацию
+// по флагу --format и пишет в stdout или файл. Обработчик `GET /api/export`
+// эти реализации не использует и формирует ответ своим кодом.
+type Exporter interface {
+	Write(w io.Writer, bookmarks []BookmarkRecord) error
+}
+
+// exporters — реестр доступных форматов для флага --format.
+var exporters = map[string]Exporter{
+	"table": tableExporter{},
+	"csv":   csvExporter{},
+	"json":  jsonExporter{},
+	"md":    markdownExporter{},
+	"html":  htmlExporter{},
+}
+
+// tableExporter воспроизводит прежний консольный вывод PrintBookmarks с
+// фиксированной шириной колонок.
+type tableExporter struct{}
+
+func (tableExporter) Write(w io.Writer, bookmarks []BookmarkRecord) error {
+	fmt.Fprintln(w, "Список закладок:")
+	fmt.Fprintln(w, "-----------------------------------------------------------------------")
+	fmt.Fprintf(w, "| %-30s | %-40s |\n", "Название", "URL")
+	fmt.Fprintln(w, "-----------------------------------------------------------------------")
+
+	for _, bookmark := range bookmarks {
+		name := bookmark.Name
+		if len(name) > 27 {
+			name = name[:24] + "..."
+		}
+
+		url := bookmark.URL
+		if len(url) > 37 {
+			url = url[:34] + "..."
+		}
+
+		fmt.Fprintf(w, "| %-30s | %-40s |\n", name, url)
+	}
+
+	fmt.Fprintln(w, "-----------------------------------------------------------------------")
+	fmt.Fprintf(w, "Всего закладок: %d\n", len(bookmarks))
+
+	return nil
+}
+
+// csvExporter пишет закладки в CSV, используя теги `csv` на BookmarkRecord.
+type csvExporter struct{}
+
+func (csvExporter) Write(w io.Writer, bookmarks []BookmarkRecord) error {
+	if err := gocsv.Marshal(bookmarks, w); err != nil {
+		return fmt.Errorf("ошибка записи CSV: %w", err)
+	}
+	return nil
+}
+
+// jsonExporter пишет закладки как отформatированный JSON-массив.
+type jsonExporter struct{}
+
+func (jsonExporter) Write(w io.Writer, bookmarks []BookmarkRecord) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(bookmarks); err != nil {
+		return fmt.Errorf("ошибка записи JSON: %w", err)
+	}
+	return nil
+}
+
+// markdownExporter группирует закладки по FolderPath и пишет их списком
+// ссылок `- [name](url)` под заголовком папки.
+type markdownExporter struct{}
+
+func (markdownExporter) Write(w io.Writer, bookmarks []BookmarkRecord) error {
+	var order []string
+	groups := map[string][]BookmarkRecord{}
+
+	for _, b := range bookmarks {
+		if _, ok := groups[b.FolderPath]; !ok {
+			order = append(order, b.FolderPath)
+		}
+		groups[b.FolderPath] = append(groups[b.FolderPath], b)
+	}
+
+	for _, folder := range order {
+		if folder != "" {
+			fmt.Fprintf(w, "## %s\n\n", folder)
+		}
+		for _, b := range groups[folder] {
+			fmt.Fprintf(w, "- [%s](%s)\n", b.Name, b.URL)
+		}
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+// htmlExporter отдаёт закладки в формате Netscape Bookmark (см. netscape.go).
+type htmlExporter struct{}
+
+func (htmlExporter) Write(w io.Writer, bookmarks []BookmarkRecord) error {
+	return ExportNetscapeHTML(w, buildFolderTree(bookmarks))
+}
+
+// runExportCmd обрабатывает подкоманду `export`: читает текущее содержимое
+// SQLite и записывает его в выбранном формате (--format), по умолчанию —
+// в формате Netscape HTML, совместимом с большинством браузеров и
+// менеджеров закладок.
+func runExportCmd(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dbPath := fs.String("db", "bookmarks.db", "путь к файлу базы данных SQLite")
+	output := fs.String("output", "", "файл для записи результата (по умолчанию stdout)")
+	format := fs.String("format", "html", "формат экспорта: table, csv, json, md, html")
+	fs.Parse(args)
+
+	exporter, ok := exporters[*format]
+	if !ok {
+		log.Fatalf("неизвестный формат экспорта %q (доступны: table, csv, json, md, html)", *format)
+	}
+
+	db, err := initDB(*dbPath)
+	if err != nil {
+		log.Fatalf("ошибка инициализации базы данных: %v", err)
+	}
+	defer db.Close()
+
+	records, err := GetAllBookmarks(db)
+	if err != nil {
+		log.Fatalf("ошибка получения закладок: %v", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("ошибка создания файла: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := exporter.Write(out, records); err != nil {
+		log.Fatalf("ошибка экспорта закладок: %v", err)
+	}
+}
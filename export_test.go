@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var exportTestBookmarks = []BookmarkRecord{
+	{ID: 1, Name: "Example", URL: "https://example.com", FolderPath: "Work", Tags: Tags{"a", "b"}},
+	{ID: 2, Name: "Root bookmark", URL: "https://root.example.com"},
+}
+
+func TestTableExporter(t *testing.T) {
+	var out strings.Builder
+	if err := (tableExporter{}).Write(&out, exportTestBookmarks); err != nil {
+		t.Fatalf("ошибка экспорта в table: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "Example") || !strings.Contains(got, "https://example.com") {
+		t.Errorf("в табличном выводе отсутствует закладка Example:\n%s", got)
+	}
+	if !strings.Contains(got, "Всего закладок: 2") {
+		t.Errorf("неверный итог в табличном выводе:\n%s", got)
+	}
+}
+
+func TestCSVExporter(t *testing.T) {
+	var out strings.Builder
+	if err := (csvExporter{}).Write(&out, exportTestBookmarks); err != nil {
+		t.Fatalf("ошибка экспорта в CSV: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "Example,https://example.com,Work,\"a,b\"") {
+		t.Errorf("неверная строка CSV для Example:\n%s", got)
+	}
+}
+
+func TestJSONExporter(t *testing.T) {
+	var out strings.Builder
+	if err := (jsonExporter{}).Write(&out, exportTestBookmarks); err != nil {
+		t.Fatalf("ошибка экспорта в JSON: %v", err)
+	}
+
+	var records []BookmarkRecord
+	if err := json.Unmarshal([]byte(out.String()), &records); err != nil {
+		t.Fatalf("результат jsonExporter не разбирается как JSON: %v", err)
+	}
+	if len(records) != 2 || records[0].Name != "Example" {
+		t.Errorf("неверный результат jsonExporter: %+v", records)
+	}
+}
+
+func TestMarkdownExporter(t *testing.T) {
+	var out strings.Builder
+	if err := (markdownExporter{}).Write(&out, exportTestBookmarks); err != nil {
+		t.Fatalf("ошибка экспорта в Markdown: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "## Work") {
+		t.Errorf("в выводе отсутствует заголовок папки Work:\n%s", got)
+	}
+	if !strings.Contains(got, "- [Example](https://example.com)") {
+		t.Errorf("в выводе отсутствует ссылка на Example:\n%s", got)
+	}
+	if !strings.Contains(got, "- [Root bookmark](https://root.example.com)") {
+		t.Errorf("в выводе отсутствует ссылка на закладку без папки:\n%s", got)
+	}
+}
+
+func TestHTMLExporter(t *testing.T) {
+	var out strings.Builder
+	if err := (htmlExporter{}).Write(&out, exportTestBookmarks); err != nil {
+		t.Fatalf("ошибка экспорта в HTML: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, `<H3>Work</H3>`) {
+		t.Errorf("в выводе отсутствует папка Work:\n%s", got)
+	}
+	if !strings.Contains(got, `HREF="https://example.com"`) {
+		t.Errorf("в выводе отсутствует закладка Example:\n%s", got)
+	}
+	if !strings.Contains(got, `HREF="https://root.example.com"`) {
+		t.Errorf("в выводе отсутствует закладка без папки:\n%s", got)
+	}
+}
@@ -3,10 +3,12 @@ package main
 import (
 	"database/sql"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	_ "github.com/glebarez/go-sqlite"
 )
@@ -40,24 +42,12 @@ type BookmarkItem struct {
 	Type      string         `json:"type"`
 	URL       string         `json:"url,omitempty"`
 	Children  []BookmarkItem `json:"children,omitempty"`
-}
-
-// getBookmarksFilePath возвращает путь к файлу закладок Chrome
-func getBookmarksFilePath() (string, error) {
-	// Путь к файлу закладок Chrome в Windows
-	userProfile := os.Getenv("USERPROFILE")
-	if userProfile == "" {
-		return "", fmt.Errorf("не удалось получить переменную окружения USERPROFILE")
-	}
 
-	bookmarksPath := filepath.Join(userProfile, "AppData", "Local", "Google", "Chrome", "User Data", "Default", "Bookmarks")
-
-	// Проверяем существование файла
-	if _, err := os.Stat(bookmarksPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("файл закладок не найден по пути: %s", bookmarksPath)
-	}
-
-	return bookmarksPath, nil
+	// FolderPath и Tags не приходят из исходного JSON Chrome, а
+	// вычисляются при обходе дерева (см. extractBookmarksTagged),
+	// чтобы их можно было сохранить в БД и отдать в Netscape-экспорт.
+	FolderPath string   `json:"-"`
+	Tags       []string `json:"-"`
 }
 
 // parseBookmarks парсит файл закладок Chrome
@@ -76,48 +66,34 @@ func parseBookmarks(filePath string) (*Bookmarks, error) {
 	return &bookmarks, nil
 }
 
-// initDB инициализирует базу данных SQLite
-func initDB(dbPath string) (*sql.DB, error) {
-	// Удаляем существующий файл базы данных, если он существует
-	if _, err := os.Stat(dbPath); err == nil {
-		err = os.Remove(dbPath)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка удаления существующей базы данных: %w", err)
-		}
-	}
-
-	// Открываем соединение с базой данных
-	db, err := sql.Open("sqlite", dbPath)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка открытия базы данных: %w", err)
-	}
-
-	// Создаем таблицу для закладок с двумя колонками
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS bookmarks (
-		name TEXT NOT NULL,
-		url TEXT NOT NULL PRIMARY KEY
-	);`
-
-	_, err = db.Exec(createTableSQL)
-	if err != nil {
-		db.Close()
-		return nil, fmt.Errorf("ошибка создания таблицы: %w", err)
-	}
-
-	return db, nil
-}
-
 // extractBookmarks рекурсивно извлекает закладки из структуры
 func extractBookmarks(items []BookmarkItem) []BookmarkItem {
+	return extractBookmarksTagged(items, "", false)
+}
+
+// extractBookmarksTagged рекурсивно извлекает закладки из структуры,
+// одновременно заполняя FolderPath именем родительских папок ("A/B/C")
+// и, если generateTag установлен, добавляя имя каждой родительской
+// папки в Tags закладки.
+func extractBookmarksTagged(items []BookmarkItem, folderPath string, generateTag bool) []BookmarkItem {
 	var result []BookmarkItem
 
 	for _, item := range items {
 		if item.Type == "url" {
+			if folderPath != "" {
+				item.FolderPath = folderPath
+			}
+			if generateTag && folderPath != "" {
+				item.Tags = append(item.Tags, strings.Split(folderPath, "/")...)
+			}
 			result = append(result, item)
 		} else if item.Type == "folder" && len(item.Children) > 0 {
 			// Рекурсивно обрабатываем вложенные папки
-			childBookmarks := extractBookmarks(item.Children)
+			childPath := item.Name
+			if folderPath != "" {
+				childPath = folderPath + "/" + item.Name
+			}
+			childBookmarks := extractBookmarksTagged(item.Children, childPath, generateTag)
 			result = append(result, childBookmarks...)
 		}
 	}
@@ -125,95 +101,183 @@ func extractBookmarks(items []BookmarkItem) []BookmarkItem {
 	return result
 }
 
-// saveBookmarksToDB сохраняет закладки в базу данных
+// saveBookmarksToDB сохраняет закладки в базу данных через UPSERT: новые
+// URL вставляются с created_at=now, у существующих modified_at
+// обновляется только если реально изменились name/folder_path/tags или
+// запись, ранее помеченная удалённой, вернулась в источник (тогда
+// deleted_at сбрасывается), а
+// URL, пропавшие из источника, не удаляются физически — для них
+// проставляется deleted_at (см. Vacuum для последующей сборки мусора).
 func saveBookmarksToDB(db *sql.DB, bookmarks []BookmarkItem) error {
-	// Подготавливаем SQL запрос для вставки
-	stmt, err := db.Prepare("INSERT OR REPLACE INTO bookmarks(name, url) VALUES(?, ?)")
+	tx, err := db.Begin()
 	if err != nil {
-		return fmt.Errorf("ошибка подготовки SQL запроса: %w", err)
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	seenURLs := make(map[string]bool, len(bookmarks))
 
-	// Вставляем каждую закладку в базу данных
 	for _, bookmark := range bookmarks {
-		_, err = stmt.Exec(bookmark.Name, bookmark.URL)
-		if err != nil {
-			return fmt.Errorf("ошибка вставки закладки: %w", err)
+		seenURLs[bookmark.URL] = true
+		tags := strings.Join(bookmark.Tags, ",")
+
+		var id int64
+		var name, folderPath, existingTags string
+		var deletedAt sql.NullString
+		err := tx.QueryRow(
+			"SELECT id, name, folder_path, tags, deleted_at FROM bookmarks WHERE url = ?",
+			bookmark.URL,
+		).Scan(&id, &name, &folderPath, &existingTags, &deletedAt)
+
+		switch {
+		case err == sql.ErrNoRows:
+			_, err = tx.Exec(
+				"INSERT INTO bookmarks(name, url, folder_path, tags, created_at) VALUES (?, ?, ?, ?, ?)",
+				bookmark.Name, bookmark.URL, bookmark.FolderPath, tags, now,
+			)
+			if err != nil {
+				return fmt.Errorf("ошибка вставки закладки: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("ошибка поиска закладки по URL: %w", err)
+		default:
+			changed := name != bookmark.Name || folderPath != bookmark.FolderPath || existingTags != tags || deletedAt.Valid
+			if changed {
+				_, err = tx.Exec(
+					"UPDATE bookmarks SET name = ?, folder_path = ?, tags = ?, modified_at = ?, deleted_at = NULL WHERE id = ?",
+					bookmark.Name, bookmark.FolderPath, tags, now, id,
+				)
+				if err != nil {
+					return fmt.Errorf("ошибка обновления закладки: %w", err)
+				}
+			}
 		}
 	}
 
-	return nil
-}
-
-// GetAllBookmarks получает все закладки из базы данных
-func GetAllBookmarks(db *sql.DB) ([]BookmarkRecord, error) {
-	rows, err := db.Query("SELECT name, url FROM bookmarks")
+	// Помечаем как удалённые (soft delete) URL, которые пропали из источника.
+	rows, err := tx.Query("SELECT url FROM bookmarks WHERE deleted_at IS NULL")
 	if err != nil {
-		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
+		return fmt.Errorf("ошибка выборки текущих закладок: %w", err)
 	}
-	defer rows.Close()
-
-	var bookmarks []BookmarkRecord
+	var staleURLs []string
 	for rows.Next() {
-		var bookmark BookmarkRecord
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			rows.Close()
+			return fmt.Errorf("ошибка сканирования URL: %w", err)
+		}
+		if !seenURLs[url] {
+			staleURLs = append(staleURLs, url)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("ошибка при итерации по URL: %w", err)
+	}
+	rows.Close()
 
-		err := rows.Scan(&bookmark.Name, &bookmark.URL)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+	for _, url := range staleURLs {
+		if _, err := tx.Exec("UPDATE bookmarks SET deleted_at = ? WHERE url = ?", now, url); err != nil {
+			return fmt.Errorf("ошибка пометки закладки удалённой: %w", err)
 		}
+	}
 
-		bookmarks = append(bookmarks, bookmark)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка подтверждения транзакции: %w", err)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	return nil
+}
+
+// GetAllBookmarks получает все неудалённые закладки из базы данных
+func GetAllBookmarks(db *sql.DB) ([]BookmarkRecord, error) {
+	rows, err := db.Query("SELECT id, name, url, folder_path, tags FROM bookmarks WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
 	}
+	defer rows.Close()
 
-	return bookmarks, nil
+	return scanBookmarkRows(rows)
 }
 
 // BookmarkRecord представляет запись о закладке в базе данных
 type BookmarkRecord struct {
-	Name string
-	URL  string
+	ID         int64  `csv:"id" json:"id"`
+	Name       string `csv:"name" json:"name"`
+	URL        string `csv:"url" json:"url"`
+	FolderPath string `csv:"folder_path" json:"folder_path"`
+	Tags       Tags   `csv:"tags" json:"tags"`
 }
 
-// PrintBookmarks выводит список закладок в консоль
+// Tags — список тегов закладки. Реализует интерфейс TypeMarshaller
+// gocarina/gocsv, чтобы записываться в CSV одной колонкой через запятую.
+type Tags []string
+
+func (t Tags) MarshalCSV() (string, error) {
+	return strings.Join(t, ","), nil
+}
+
+func (t *Tags) UnmarshalCSV(s string) error {
+	if s == "" {
+		*t = nil
+		return nil
+	}
+	*t = strings.Split(s, ",")
+	return nil
+}
+
+// PrintBookmarks выводит список закладок в консоль в виде таблицы
+// фиксированной ширины (см. tableExporter в export.go).
 func PrintBookmarks(db *sql.DB) error {
 	bookmarks, err := GetAllBookmarks(db)
 	if err != nil {
 		return fmt.Errorf("ошибка получения закладок: %w", err)
 	}
 
-	fmt.Println("Список закладок:")
-	fmt.Println("-----------------------------------------------------------------------")
-	fmt.Printf("| %-30s | %-40s |\n", "Название", "URL")
-	fmt.Println("-----------------------------------------------------------------------")
-
-	for _, bookmark := range bookmarks {
-		// Обрезаем длинные названия и URL для красивого вывода
-		name := bookmark.Name
-		if len(name) > 27 {
-			name = name[:24] + "..."
-		}
+	return tableExporter{}.Write(os.Stdout, bookmarks)
+}
 
-		url := bookmark.URL
-		if len(url) > 37 {
-			url = url[:34] + "..."
+func main() {
+	// Первый аргумент может задавать подкоманду (import/export/watch/serve); без
+	// подкоманды сохраняется прежнее поведение — разовая синхронизация
+	// закладок браузера в SQLite.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			runImportCmd(os.Args[2:])
+			return
+		case "export":
+			runExportCmd(os.Args[2:])
+			return
+		case "watch":
+			runWatchCmd(os.Args[2:])
+			return
+		case "serve":
+			runServeCmd(os.Args[2:])
+			return
 		}
-
-		fmt.Printf("| %-30s | %-40s |\n", name, url)
 	}
 
-	fmt.Println("-----------------------------------------------------------------------")
-	fmt.Printf("Всего закладок: %d\n", len(bookmarks))
-
-	return nil
+	runSync(os.Args[1:])
 }
 
-func main() {
+// runSync находит файл закладок выбранного браузера (по умолчанию —
+// Chrome), парсит его и сохраняет результат в SQLite.
+func runSync(args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	browser := fs.String("browser", "chrome", "источник закладок: chrome, chromium, edge, brave, vivaldi, firefox, safari")
+	profile := fs.String("profile", "", "имя профиля браузера (по умолчанию — профиль по умолчанию)")
+	gc := fs.Bool("gc", false, "удалить записи, помеченные на удаление более 30 дней назад")
+	fs.Parse(args)
+
+	source, err := getBrowserSource(*browser)
+	if err != nil {
+		log.Fatalf("Ошибка: %v", err)
+	}
+
 	// Получаем путь к файлу закладок
-	bookmarksPath, err := getBookmarksFilePath()
+	bookmarksPath, err := source.ResolvePath(*profile)
 	if err != nil {
 		log.Fatalf("Ошибка: %v", err)
 	}
@@ -221,7 +285,7 @@ func main() {
 	fmt.Printf("Найден файл закладок: %s\n", bookmarksPath)
 
 	// Парсим файл закладок
-	bookmarks, err := parseBookmarks(bookmarksPath)
+	items, err := source.Load(bookmarksPath)
 	if err != nil {
 		log.Fatalf("Ошибка при парсинге закладок: %v", err)
 	}
@@ -234,11 +298,8 @@ func main() {
 	}
 	defer db.Close()
 
-	// Извлекаем все закладки из всех разделов
-	var allBookmarks []BookmarkItem
-	allBookmarks = append(allBookmarks, extractBookmarks(bookmarks.Roots.BookmarkBar.Children)...)
-	allBookmarks = append(allBookmarks, extractBookmarks(bookmarks.Roots.Other.Children)...)
-	allBookmarks = append(allBookmarks, extractBookmarks(bookmarks.Roots.Synced.Children)...)
+	// Извлекаем все закладки из дерева, полученного от источника
+	allBookmarks := extractBookmarks(items)
 
 	// Сохраняем закладки в базу данных
 	err = saveBookmarksToDB(db, allBookmarks)
@@ -248,6 +309,15 @@ func main() {
 
 	fmt.Printf("Успешно сохранено %d закладок в базу данных %s\n", len(allBookmarks), dbPath)
 
+	if *gc {
+		removed, err := Vacuum(db, 30*24*time.Hour)
+		if err != nil {
+			log.Printf("Ошибка очистки удалённых закладок: %v", err)
+		} else {
+			fmt.Printf("Удалено записей, помеченных на удаление: %d\n", removed)
+		}
+	}
+
 	// Выводим список закладок в консоль
 	err = PrintBookmarks(db)
 	if err != nil {
@@ -5,30 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
-// TestGetBookmarksFilePath проверяет функцию получения пути к файлу закладок
-func TestGetBookmarksFilePath(t *testing.T) {
-	path, err := getBookmarksFilePath()
-
-	// Проверяем только логику функции, не требуя наличия реального файла
-	if err != nil {
-		// Если файл не найден, это нормально для тестового окружения
-		// Проверяем, что путь содержит ожидаемые компоненты
-		userProfile := os.Getenv("USERPROFILE")
-		expectedPath := filepath.Join(userProfile, "AppData", "Local", "Google", "Chrome", "User Data", "Default", "Bookmarks")
-
-		if expectedPath != path {
-			t.Errorf("Неверный путь к файлу закладок. Получено: %s, ожидалось: %s", path, expectedPath)
-		}
-	} else {
-		// Если файл найден, проверяем, что путь не пустой
-		if path == "" {
-			t.Error("Получен пустой путь к файлу закладок")
-		}
-	}
-}
-
 // TestExtractBookmarks проверяет функцию извлечения закладок
 func TestExtractBookmarks(t *testing.T) {
 	// Создаем тестовую структуру закладок
@@ -117,7 +96,8 @@ func TestInitDB(t *testing.T) {
 	columns := make(map[string]bool)
 	for rows.Next() {
 		var cid, notnull, pk int
-		var name, type_, dflt_value string
+		var name, type_ string
+		var dflt_value sql.NullString
 		err = rows.Scan(&cid, &name, &type_, &notnull, &dflt_value, &pk)
 		if err != nil {
 			t.Fatalf("Ошибка сканирования строки: %v", err)
@@ -126,7 +106,7 @@ func TestInitDB(t *testing.T) {
 	}
 
 	// Проверяем наличие всех необходимых столбцов
-	requiredColumns := []string{"id", "name", "url", "date_added"}
+	requiredColumns := []string{"id", "name", "url", "folder_path", "tags", "created_at", "modified_at", "deleted_at"}
 	for _, col := range requiredColumns {
 		if !columns[col] {
 			t.Errorf("В таблице отсутствует столбец %s", col)
@@ -136,3 +116,50 @@ func TestInitDB(t *testing.T) {
 	// Удаляем временный файл
 	os.Remove(dbPath)
 }
+
+// TestVacuum проверяет, что Vacuum удаляет только закладки, помеченные на
+// удаление раньше переданного порога, и не трогает остальные.
+func TestVacuum(t *testing.T) {
+	tempDir := os.TempDir()
+	dbPath := filepath.Join(tempDir, "test_vacuum.db")
+	os.Remove(dbPath)
+	defer os.Remove(dbPath)
+
+	db, err := initDB(dbPath)
+	if err != nil {
+		t.Fatalf("Ошибка инициализации базы данных: %v", err)
+	}
+	defer db.Close()
+
+	old := time.Now().UTC().Add(-60 * 24 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().UTC().Add(-1 * time.Hour).Format(time.RFC3339)
+
+	if _, err := db.Exec(
+		"INSERT INTO bookmarks(name, url, created_at, deleted_at) VALUES (?, ?, ?, ?)",
+		"Старая закладка", "https://old.example.com", old, old,
+	); err != nil {
+		t.Fatalf("Ошибка вставки тестовой закладки: %v", err)
+	}
+	if _, err := db.Exec(
+		"INSERT INTO bookmarks(name, url, created_at, deleted_at) VALUES (?, ?, ?, ?)",
+		"Недавно удалённая закладка", "https://recent.example.com", recent, recent,
+	); err != nil {
+		t.Fatalf("Ошибка вставки тестовой закладки: %v", err)
+	}
+
+	removed, err := Vacuum(db, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Ошибка Vacuum: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Неверное число удалённых строк. Получено: %d, ожидалось: %d", removed, 1)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM bookmarks WHERE url = ?", "https://recent.example.com").Scan(&count); err != nil {
+		t.Fatalf("Ошибка проверки оставшейся закладки: %v", err)
+	}
+	if count != 1 {
+		t.Error("Vacuum удалил закладку, помеченную на удаление менее 30 дней назад")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/glebarez/go-sqlite"
+)
+
+// migration описывает один шаг эволюции схемы. SQL выполняется целиком в
+// рамках одной транзакции; version должен строго возрастать и никогда не
+// переиспользоваться — это история схемы базы, а не её текущий снимок.
+type migration struct {
+	Version int
+	SQL     string
+}
+
+// migrations — история схемы bookmarks в порядке применения. Новые
+// миграции добавляются в конец списка; существующие записи менять нельзя.
+var migrations = []migration{
+	{
+		Version: 1,
+		SQL: `CREATE TABLE IF NOT EXISTS bookmarks (
+			name TEXT NOT NULL,
+			url TEXT NOT NULL PRIMARY KEY
+		);`,
+	},
+	{
+		Version: 2,
+		SQL:     `ALTER TABLE bookmarks ADD COLUMN folder_path TEXT NOT NULL DEFAULT '';`,
+	},
+	{
+		Version: 3,
+		SQL:     `ALTER TABLE bookmarks ADD COLUMN tags TEXT NOT NULL DEFAULT '';`,
+	},
+	{
+		// Переходим с url как первичного ключа на суррогатный id и
+		// добавляем поля истории (created_at/modified_at/deleted_at),
+		// необходимые для soft-delete. SQLite не умеет менять
+		// первичный ключ существующей таблицы, поэтому таблица
+		// пересобирается целиком.
+		Version: 4,
+		SQL: `
+			CREATE TABLE bookmarks_new (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL,
+				url TEXT NOT NULL UNIQUE,
+				folder_path TEXT NOT NULL DEFAULT '',
+				tags TEXT NOT NULL DEFAULT '',
+				created_at TEXT,
+				modified_at TEXT,
+				deleted_at TEXT
+			);
+			INSERT INTO bookmarks_new (name, url, folder_path, tags)
+				SELECT name, url, folder_path, tags FROM bookmarks;
+			DROP TABLE bookmarks;
+			ALTER TABLE bookmarks_new RENAME TO bookmarks;
+		`,
+	},
+}
+
+// initDB открывает (или создаёт) файл базы данных SQLite и приводит его
+// схему к актуальному состоянию, применяя ещё не накатанные миграции из
+// migrations. В отличие от прежней реализации, существующая база не
+// удаляется — это позволяет сохранять историю закладок между запусками.
+func initDB(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия базы данных: %w", err)
+	}
+
+	if err := runMigrations(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// runMigrations применяет все миграции из migrations, версия которых ещё
+// не отмечена в schema_migrations, каждую в своей транзакции.
+func runMigrations(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	);`); err != nil {
+		return fmt.Errorf("ошибка создания таблицы schema_migrations: %w", err)
+	}
+
+	applied := map[int]bool{}
+	rows, err := db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return fmt.Errorf("ошибка чтения применённых миграций: %w", err)
+	}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return fmt.Errorf("ошибка сканирования версии миграции: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("ошибка при итерации по schema_migrations: %w", err)
+	}
+	rows.Close()
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("ошибка начала транзакции для миграции %d: %w", m.Version, err)
+		}
+
+		if _, err := tx.Exec(m.SQL); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("ошибка применения миграции %d: %w", m.Version, err)
+		}
+
+		now := time.Now().UTC().Format(time.RFC3339)
+		if _, err := tx.Exec("INSERT INTO schema_migrations(version, applied_at) VALUES (?, ?)", m.Version, now); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("ошибка записи миграции %d в schema_migrations: %w", m.Version, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("ошибка подтверждения миграции %d: %w", m.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// Vacuum окончательно удаляет закладки, помеченные на удаление
+// (deleted_at) раньше, чем olderThan назад от текущего момента, и
+// возвращает число удалённых строк. Вызывается через флаг --gc.
+func Vacuum(db *sql.DB, olderThan time.Duration) (int64, error) {
+	cutoff := time.Now().UTC().Add(-olderThan).Format(time.RFC3339)
+
+	res, err := db.Exec("DELETE FROM bookmarks WHERE deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка очистки удалённых закладок: %w", err)
+	}
+
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения числа удалённых строк: %w", err)
+	}
+
+	return removed, nil
+}
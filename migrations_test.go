@@ -0,0 +1,127 @@
+package main
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSaveBookmarksToDBUpsertAndSoftDelete проверяет переходы
+// created_at/modified_at/deleted_at в saveBookmarksToDB при повторных
+// вызовах: неизменившийся URL не трогается, изменившийся получает
+// modified_at, пропавший помечается deleted_at, а вернувшийся —
+// восстанавливается (deleted_at сбрасывается).
+func TestSaveBookmarksToDBUpsertAndSoftDelete(t *testing.T) {
+	tempDir := os.TempDir()
+	dbPath := filepath.Join(tempDir, "test_save_upsert.db")
+	os.Remove(dbPath)
+	defer os.Remove(dbPath)
+
+	db, err := initDB(dbPath)
+	if err != nil {
+		t.Fatalf("Ошибка инициализации базы данных: %v", err)
+	}
+	defer db.Close()
+
+	first := []BookmarkItem{
+		{Type: "url", Name: "Unchanged", URL: "https://unchanged.example.com"},
+		{Type: "url", Name: "Old name", URL: "https://renamed.example.com"},
+		{Type: "url", Name: "Removed", URL: "https://removed.example.com"},
+	}
+	if err := saveBookmarksToDB(db, first); err != nil {
+		t.Fatalf("Ошибка первого сохранения: %v", err)
+	}
+
+	var createdAt string
+	var modifiedAt, deletedAt sql.NullString
+	scanRow := func(url string) (string, sql.NullString, sql.NullString) {
+		var c string
+		var m, d sql.NullString
+		if err := db.QueryRow(
+			"SELECT created_at, modified_at, deleted_at FROM bookmarks WHERE url = ?", url,
+		).Scan(&c, &m, &d); err != nil {
+			t.Fatalf("Ошибка чтения закладки %s: %v", url, err)
+		}
+		return c, m, d
+	}
+
+	createdAt, modifiedAt, deletedAt = scanRow("https://unchanged.example.com")
+	if createdAt == "" {
+		t.Error("у новой закладки должен быть установлен created_at")
+	}
+	if modifiedAt.Valid || deletedAt.Valid {
+		t.Error("у только что вставленной закладки modified_at/deleted_at должны быть пустыми")
+	}
+
+	second := []BookmarkItem{
+		{Type: "url", Name: "Unchanged", URL: "https://unchanged.example.com"},
+		{Type: "url", Name: "New name", URL: "https://renamed.example.com"},
+	}
+	if err := saveBookmarksToDB(db, second); err != nil {
+		t.Fatalf("Ошибка второго сохранения: %v", err)
+	}
+
+	_, modifiedAt, deletedAt = scanRow("https://unchanged.example.com")
+	if modifiedAt.Valid {
+		t.Error("у неизменившейся закладки modified_at не должен выставляться")
+	}
+	if deletedAt.Valid {
+		t.Error("неизменившаяся закладка не должна помечаться удалённой")
+	}
+
+	_, modifiedAt, deletedAt = scanRow("https://renamed.example.com")
+	if !modifiedAt.Valid {
+		t.Error("у переименованной закладки должен быть установлен modified_at")
+	}
+	if deletedAt.Valid {
+		t.Error("переименованная закладка не должна помечаться удалённой")
+	}
+
+	_, _, deletedAt = scanRow("https://removed.example.com")
+	if !deletedAt.Valid {
+		t.Error("пропавшая из источника закладка должна быть помечена deleted_at")
+	}
+
+	third := []BookmarkItem{
+		{Type: "url", Name: "Unchanged", URL: "https://unchanged.example.com"},
+		{Type: "url", Name: "New name", URL: "https://renamed.example.com"},
+		{Type: "url", Name: "Removed", URL: "https://removed.example.com"},
+	}
+	if err := saveBookmarksToDB(db, third); err != nil {
+		t.Fatalf("Ошибка третьего сохранения: %v", err)
+	}
+
+	_, _, deletedAt = scanRow("https://removed.example.com")
+	if deletedAt.Valid {
+		t.Error("вернувшаяся в источник закладка должна сбрасывать deleted_at")
+	}
+}
+
+// TestRunMigrationsIdempotent проверяет, что повторный вызов runMigrations
+// на уже мигрированной базе не возвращает ошибку и не применяет миграции
+// повторно.
+func TestRunMigrationsIdempotent(t *testing.T) {
+	tempDir := os.TempDir()
+	dbPath := filepath.Join(tempDir, "test_migrations_idempotent.db")
+	os.Remove(dbPath)
+	defer os.Remove(dbPath)
+
+	db, err := initDB(dbPath)
+	if err != nil {
+		t.Fatalf("Ошибка инициализации базы данных: %v", err)
+	}
+	defer db.Close()
+
+	if err := runMigrations(db); err != nil {
+		t.Fatalf("Повторный runMigrations вернул ошибку: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil {
+		t.Fatalf("Ошибка чтения schema_migrations: %v", err)
+	}
+	if count != len(migrations) {
+		t.Errorf("Неверное число применённых миграций. Получено: %d, ожидалось: %d", count, len(migrations))
+	}
+}
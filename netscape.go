@@ -0,0 +1,243 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ImportNetscapeHTML парсит файл закладок в стандартном формате Netscape
+// (`<DL><DT><A HREF="..." ADD_DATE="..." TAGS="...">Name</A>`), который
+// экспортируют Chrome, Firefox, Shiori, Pocket и большинство других
+// менеджеров закладок. Папки (`<H3>`) обходятся рекурсивно, чтобы
+// восстановить дерево: каждая родительская `<H3>` превращается в папку
+// `BookmarkItem` с заполненным Children.
+func ImportNetscapeHTML(r io.Reader) ([]BookmarkItem, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка парсинга Netscape HTML: %w", err)
+	}
+
+	// Корневой список закладок лежит в первом <DL> документа.
+	root := doc.Find("dl").First()
+	if root.Length() == 0 {
+		return nil, fmt.Errorf("не найден корневой элемент <DL>")
+	}
+
+	return parseNetscapeList(root), nil
+}
+
+// parseNetscapeList разбирает один уровень <DL> с дочерними <DT>,
+// рекурсивно спускаясь во вложенные <DL> для папок. Генерация тегов из
+// имён папок делается отдельно, через extractBookmarksTagged, а не здесь.
+func parseNetscapeList(dl *goquery.Selection) []BookmarkItem {
+	var items []BookmarkItem
+
+	dl.ChildrenFiltered("dt").Each(func(_ int, dt *goquery.Selection) {
+		if h3 := dt.ChildrenFiltered("h3").First(); h3.Length() > 0 {
+			folder := BookmarkItem{
+				Type: "folder",
+				Name: strings.TrimSpace(h3.Text()),
+			}
+
+			// Вложенный список папки — это следующий <DL>-сосед <H3>
+			// внутри того же <DT> (браузеры кладут его именно туда).
+			childDL := dt.ChildrenFiltered("dl").First()
+			if childDL.Length() > 0 {
+				folder.Children = parseNetscapeList(childDL)
+			}
+
+			items = append(items, folder)
+			return
+		}
+
+		if a := dt.ChildrenFiltered("a").First(); a.Length() > 0 {
+			item := BookmarkItem{
+				Type: "url",
+				Name: strings.TrimSpace(a.Text()),
+			}
+			item.URL, _ = a.Attr("href")
+			item.DateAdded, _ = a.Attr("add_date")
+			if tags, ok := a.Attr("tags"); ok && tags != "" {
+				item.Tags = strings.Split(tags, ",")
+			}
+
+			items = append(items, item)
+		}
+	})
+
+	return items
+}
+
+// ExportNetscapeHTML записывает items в стандартном формате Netscape
+// Bookmark, который понимают Chrome, Firefox, Shiori и Pocket.
+// Папки (элементы с Type "folder" и их Children) выводятся как
+// <DT><H3>...</H3> с вложенным <DL><p>...</DL><p>. Поле FolderPath
+// закладок при этом не используется.
+func ExportNetscapeHTML(w io.Writer, items []BookmarkItem) error {
+	if _, err := io.WriteString(w, netscapeHeader); err != nil {
+		return fmt.Errorf("ошибка записи заголовка: %w", err)
+	}
+
+	if err := writeNetscapeList(w, items, 1); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "</DL><p>\n"); err != nil {
+		return fmt.Errorf("ошибка записи завершения списка: %w", err)
+	}
+
+	return nil
+}
+
+const netscapeHeader = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+`
+
+func writeNetscapeList(w io.Writer, items []BookmarkItem, indent int) error {
+	pad := strings.Repeat("    ", indent)
+
+	for _, item := range items {
+		if item.Type == "folder" {
+			fmt.Fprintf(w, "%s<DT><H3>%s</H3>\n%s<DL><p>\n", pad, escapeHTML(item.Name), pad)
+			if err := writeNetscapeList(w, item.Children, indent+1); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s</DL><p>\n", pad)
+			continue
+		}
+
+		attrs := fmt.Sprintf(`HREF="%s"`, escapeHTML(item.URL))
+		if item.DateAdded != "" {
+			attrs += fmt.Sprintf(` ADD_DATE="%s"`, item.DateAdded)
+		}
+		if len(item.Tags) > 0 {
+			attrs += fmt.Sprintf(` TAGS="%s"`, escapeHTML(strings.Join(item.Tags, ",")))
+		}
+
+		if _, err := fmt.Fprintf(w, "%s<DT><A %s>%s</A>\n", pad, attrs, escapeHTML(item.Name)); err != nil {
+			return fmt.Errorf("ошибка записи закладки: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func escapeHTML(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	return r.Replace(s)
+}
+
+// folderNode — промежуточное представление дерева папок при построении
+// buildFolderTree: в отличие от BookmarkItem, оно хранит детей по указателю,
+// так что вложенные папки можно дополнять после создания.
+type folderNode struct {
+	name       string
+	urls       []BookmarkRecord
+	childOrder []string
+	children   map[string]*folderNode
+}
+
+func newFolderNode(name string) *folderNode {
+	return &folderNode{name: name, children: map[string]*folderNode{}}
+}
+
+// toBookmarkItems рекурсивно превращает folderNode в дерево BookmarkItem,
+// вызывается только после того, как все записи разложены по узлам.
+func (n *folderNode) toBookmarkItems() []BookmarkItem {
+	var items []BookmarkItem
+
+	for _, name := range n.childOrder {
+		child := n.children[name]
+		items = append(items, BookmarkItem{
+			Type:     "folder",
+			Name:     child.name,
+			Children: child.toBookmarkItems(),
+		})
+	}
+
+	for _, rec := range n.urls {
+		items = append(items, BookmarkItem{
+			Type: "url",
+			Name: rec.Name,
+			URL:  rec.URL,
+			Tags: rec.Tags,
+		})
+	}
+
+	return items
+}
+
+// buildFolderTree группирует плоские записи БД по FolderPath, восстанавливая
+// дерево папок для ExportNetscapeHTML.
+func buildFolderTree(records []BookmarkRecord) []BookmarkItem {
+	root := newFolderNode("")
+
+	for _, rec := range records {
+		parent := root
+		if rec.FolderPath != "" {
+			for _, part := range strings.Split(rec.FolderPath, "/") {
+				child, ok := parent.children[part]
+				if !ok {
+					child = newFolderNode(part)
+					parent.children[part] = child
+					parent.childOrder = append(parent.childOrder, part)
+				}
+				parent = child
+			}
+		}
+
+		parent.urls = append(parent.urls, rec)
+	}
+
+	return root.toBookmarkItems()
+}
+
+// runImportCmd обрабатывает подкоманду `import`: читает файл закладок в
+// формате Netscape HTML и сохраняет его содержимое в SQLite.
+func runImportCmd(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	generateTag := fs.Bool("generate-tag", false, "добавлять имена всех родительских папок как теги закладки")
+	dbPath := fs.String("db", "bookmarks.db", "путь к файлу базы данных SQLite")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		log.Fatalf("использование: %s import [--generate-tag] [--db path] <файл.html>", os.Args[0])
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("ошибка открытия файла: %v", err)
+	}
+	defer f.Close()
+
+	items, err := ImportNetscapeHTML(f)
+	if err != nil {
+		log.Fatalf("ошибка импорта закладок: %v", err)
+	}
+
+	bookmarks := extractBookmarksTagged(items, "", *generateTag)
+
+	db, err := initDB(*dbPath)
+	if err != nil {
+		log.Fatalf("ошибка инициализации базы данных: %v", err)
+	}
+	defer db.Close()
+
+	if err := saveBookmarksToDB(db, bookmarks); err != nil {
+		log.Fatalf("ошибка сохранения закладок: %v", err)
+	}
+
+	fmt.Printf("Импортировано %d закладок из %s в %s\n", len(bookmarks), fs.Arg(0), *dbPath)
+}
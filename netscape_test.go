@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestImportExportNetscapeRoundTrip проверяет, что закладка внутри папки
+// переживает полный цикл импорт -> экспорт без потерь (см. buildFolderTree).
+func TestImportExportNetscapeRoundTrip(t *testing.T) {
+	const input = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<DL><p>
+    <DT><H3>Work</H3>
+    <DL><p>
+        <DT><A HREF="https://example.com" ADD_DATE="1000" TAGS="a,b">Example</A>
+    </DL><p>
+</DL><p>
+`
+
+	items, err := ImportNetscapeHTML(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ошибка импорта: %v", err)
+	}
+
+	if len(items) != 1 || items[0].Type != "folder" || items[0].Name != "Work" {
+		t.Fatalf("неверная структура импортированного дерева: %+v", items)
+	}
+	if len(items[0].Children) != 1 || items[0].Children[0].URL != "https://example.com" {
+		t.Fatalf("закладка внутри папки Work не найдена: %+v", items[0].Children)
+	}
+
+	bookmarks := extractBookmarks(items)
+	if len(bookmarks) != 1 {
+		t.Fatalf("ожидалась 1 закладка после extractBookmarks, получено %d", len(bookmarks))
+	}
+	if bookmarks[0].FolderPath != "Work" {
+		t.Errorf("неверный FolderPath. Получено: %q, ожидалось: %q", bookmarks[0].FolderPath, "Work")
+	}
+
+	records := []BookmarkRecord{
+		{Name: bookmarks[0].Name, URL: bookmarks[0].URL, FolderPath: bookmarks[0].FolderPath},
+	}
+
+	var out strings.Builder
+	if err := ExportNetscapeHTML(&out, buildFolderTree(records)); err != nil {
+		t.Fatalf("ошибка экспорта: %v", err)
+	}
+
+	html := out.String()
+	if !strings.Contains(html, `<H3>Work</H3>`) {
+		t.Errorf("в выводе отсутствует папка Work:\n%s", html)
+	}
+	if !strings.Contains(html, `HREF="https://example.com"`) {
+		t.Errorf("закладка внутри Work пропала при экспорте (см. buildFolderTree):\n%s", html)
+	}
+}
+
+// TestBuildFolderTreeNested проверяет, что buildFolderTree сохраняет
+// закладки на нескольких уровнях вложенности папок.
+func TestBuildFolderTreeNested(t *testing.T) {
+	records := []BookmarkRecord{
+		{Name: "Root bookmark", URL: "https://root.example.com"},
+		{Name: "Nested bookmark", URL: "https://nested.example.com", FolderPath: "A/B"},
+	}
+
+	tree := buildFolderTree(records)
+
+	var foundRoot, foundNested bool
+	for _, item := range tree {
+		if item.Type == "url" && item.URL == "https://root.example.com" {
+			foundRoot = true
+		}
+		if item.Type == "folder" && item.Name == "A" {
+			for _, child := range item.Children {
+				if child.Type == "folder" && child.Name == "B" {
+					for _, leaf := range child.Children {
+						if leaf.URL == "https://nested.example.com" {
+							foundNested = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if !foundRoot {
+		t.Error("закладка без папки не найдена в дереве")
+	}
+	if !foundNested {
+		t.Error("закладка из вложенной папки A/B не найдена в дереве")
+	}
+}
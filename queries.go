@@ -0,0 +1,198 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ListBookmarksPaginated получает неудалённые закладки с ограничением,
+// смещением и сортировкой. orderBy должен быть одним из allowedOrderColumns;
+// пустое или неизвестное значение трактуется как "name".
+func ListBookmarksPaginated(db *sql.DB, limit, offset int, orderBy string) ([]BookmarkRecord, error) {
+	if !allowedOrderColumns[orderBy] {
+		orderBy = "name"
+	}
+
+	query := fmt.Sprintf(
+		"SELECT id, name, url, folder_path, tags FROM bookmarks WHERE deleted_at IS NULL ORDER BY %s LIMIT ? OFFSET ?",
+		orderBy,
+	)
+	rows, err := db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBookmarkRows(rows)
+}
+
+// SearchQuery задаёт условия поиска для SearchBookmarks.
+type SearchQuery struct {
+	Text   string
+	Tag    string
+	Folder string
+	Limit  int
+	Offset int
+}
+
+// escapeLike экранирует спецсимволы оператора LIKE (% и _, а также сам
+// символ экранирования), чтобы значение из SearchQuery всегда трактовалось
+// как буквальная подстрока, а не шаблон.
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return r.Replace(s)
+}
+
+// SearchBookmarks ищет закладки по подстроке в названии/URL и точному
+// совпадению тега/папки. Пустые поля SearchQuery в фильтрации не участвуют.
+func SearchBookmarks(db *sql.DB, q SearchQuery) ([]BookmarkRecord, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+
+	if q.Text != "" {
+		conditions = append(conditions, "(name LIKE ? ESCAPE '\\' OR url LIKE ? ESCAPE '\\')")
+		like := "%" + escapeLike(q.Text) + "%"
+		args = append(args, like, like)
+	}
+	if q.Tag != "" {
+		conditions = append(conditions, "(',' || tags || ',') LIKE ? ESCAPE '\\'")
+		args = append(args, "%,"+escapeLike(q.Tag)+",%")
+	}
+	if q.Folder != "" {
+		conditions = append(conditions, "folder_path = ?")
+		args = append(args, q.Folder)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	args = append(args, limit, q.Offset)
+
+	query := fmt.Sprintf(
+		"SELECT id, name, url, folder_path, tags FROM bookmarks WHERE %s ORDER BY name LIMIT ? OFFSET ?",
+		strings.Join(conditions, " AND "),
+	)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения поискового запроса: %w", err)
+	}
+	defer rows.Close()
+
+	return scanBookmarkRows(rows)
+}
+
+// GetBookmarkByID возвращает закладку по id или nil, если она не найдена
+// либо помечена удалённой.
+func GetBookmarkByID(db *sql.DB, id int64) (*BookmarkRecord, error) {
+	var record BookmarkRecord
+	var tags string
+
+	err := db.QueryRow(
+		"SELECT id, name, url, folder_path, tags FROM bookmarks WHERE id = ? AND deleted_at IS NULL",
+		id,
+	).Scan(&record.ID, &record.Name, &record.URL, &record.FolderPath, &tags)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения закладки %d: %w", id, err)
+	}
+	if tags != "" {
+		record.Tags = strings.Split(tags, ",")
+	}
+
+	return &record, nil
+}
+
+// CreateBookmark вставляет новую закладку и возвращает её id.
+func CreateBookmark(db *sql.DB, record BookmarkRecord) (int64, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	res, err := db.Exec(
+		"INSERT INTO bookmarks(name, url, folder_path, tags, created_at) VALUES (?, ?, ?, ?, ?)",
+		record.Name, record.URL, record.FolderPath, strings.Join(record.Tags, ","), now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания закладки: %w", err)
+	}
+
+	return res.LastInsertId()
+}
+
+// ErrBookmarkNotFound возвращается UpdateBookmark/DeleteBookmark, когда id
+// не соответствует ни одной строке в таблице bookmarks.
+var ErrBookmarkNotFound = fmt.Errorf("закладка не найдена")
+
+// UpdateBookmark обновляет название/URL/папку/теги существующей закладки.
+// Возвращает ErrBookmarkNotFound, если строка с таким id не найдена.
+func UpdateBookmark(db *sql.DB, id int64, record BookmarkRecord) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	res, err := db.Exec(
+		"UPDATE bookmarks SET name = ?, url = ?, folder_path = ?, tags = ?, modified_at = ? WHERE id = ?",
+		record.Name, record.URL, record.FolderPath, strings.Join(record.Tags, ","), now, id,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления закладки %d: %w", id, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения числа изменённых строк: %w", err)
+	}
+	if affected == 0 {
+		return ErrBookmarkNotFound
+	}
+
+	return nil
+}
+
+// DeleteBookmark помечает закладку удалённой (soft delete), не удаляя
+// строку физически — см. Vacuum для последующей сборки мусора. Возвращает
+// ErrBookmarkNotFound, если строка с таким id не найдена.
+func DeleteBookmark(db *sql.DB, id int64) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	res, err := db.Exec("UPDATE bookmarks SET deleted_at = ? WHERE id = ?", now, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления закладки %d: %w", id, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения числа изменённых строк: %w", err)
+	}
+	if affected == 0 {
+		return ErrBookmarkNotFound
+	}
+
+	return nil
+}
+
+func scanBookmarkRows(rows *sql.Rows) ([]BookmarkRecord, error) {
+	var bookmarks []BookmarkRecord
+
+	for rows.Next() {
+		var bookmark BookmarkRecord
+		var tags string
+
+		if err := rows.Scan(&bookmark.ID, &bookmark.Name, &bookmark.URL, &bookmark.FolderPath, &tags); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		if tags != "" {
+			bookmark.Tags = strings.Split(tags, ",")
+		}
+
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %w", err)
+	}
+
+	return bookmarks, nil
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// apiServer держит зависимости HTTP-обработчиков API закладок.
+type apiServer struct {
+	db *sql.DB
+}
+
+// allowedOrderColumns — белый список колонок, допустимых в order_by, чтобы
+// значение из query-параметра нельзя было подставить в SQL напрямую.
+var allowedOrderColumns = map[string]bool{
+	"name":        true,
+	"url":         true,
+	"folder_path": true,
+	"created_at":  true,
+	"modified_at": true,
+}
+
+// @title Bookmark Converter API
+// @version 1.0
+// @description REST-доступ к локальной базе закладок (см. README для CLI-режима).
+// @BasePath /
+
+// newRouter собирает маршруты API поверх chi.
+func newRouter(db *sql.DB, authToken string) http.Handler {
+	s := &apiServer{db: db}
+
+	r := chi.NewRouter()
+	r.Use(tokenAuthMiddleware(authToken))
+
+	r.Route("/api/bookmarks", func(r chi.Router) {
+		r.Get("/", s.listBookmarks)
+		r.Post("/", s.createBookmark)
+		r.Get("/{id}", s.getBookmark)
+		r.Put("/{id}", s.updateBookmark)
+		r.Delete("/{id}", s.deleteBookmark)
+	})
+	r.Get("/api/search", s.searchBookmarks)
+	r.Get("/api/export", s.exportBookmarks)
+
+	return r
+}
+
+// tokenAuthMiddleware проверяет заголовок "Authorization: Bearer <token>"
+// против общего секрета. Если token пуст (переменная окружения не
+// задана), проверка отключается — это удобно для локальной разработки.
+func tokenAuthMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if token == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			got := strings.TrimPrefix(auth, "Bearer ")
+			if !strings.HasPrefix(auth, "Bearer ") || got == "" || got != token {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// listBookmarks godoc
+// @Summary      Список закладок
+// @Tags         bookmarks
+// @Produce      json
+// @Param        limit    query int    false "максимум записей (по умолчанию 50)"
+// @Param        offset   query int    false "смещение"
+// @Param        order_by query string false "name|url|folder_path|created_at|modified_at"
+// @Success      200 {array} BookmarkRecord
+// @Router       /api/bookmarks [get]
+func (s *apiServer) listBookmarks(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+	orderBy := r.URL.Query().Get("order_by")
+
+	records, err := ListBookmarksPaginated(s.db, limit, offset, orderBy)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, records)
+}
+
+// getBookmark godoc
+// @Summary      Получить закладку по id
+// @Tags         bookmarks
+// @Produce      json
+// @Param        id path int true "id закладки"
+// @Success      200 {object} BookmarkRecord
+// @Failure      404 {string} string "not found"
+// @Router       /api/bookmarks/{id} [get]
+func (s *apiServer) getBookmark(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("некорректный id: %w", err))
+		return
+	}
+
+	record, err := GetBookmarkByID(s.db, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if record == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, record)
+}
+
+// createBookmark godoc
+// @Summary      Создать закладку
+// @Tags         bookmarks
+// @Accept       json
+// @Produce      json
+// @Param        bookmark body BookmarkRecord true "новая закладка"
+// @Success      201 {object} BookmarkRecord
+// @Router       /api/bookmarks [post]
+func (s *apiServer) createBookmark(w http.ResponseWriter, r *http.Request) {
+	var record BookmarkRecord
+	if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("ошибка разбора тела запроса: %w", err))
+		return
+	}
+
+	id, err := CreateBookmark(s.db, record)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	record.ID = id
+
+	writeJSON(w, http.StatusCreated, record)
+}
+
+// updateBookmark godoc
+// @Summary      Обновить закладку
+// @Tags         bookmarks
+// @Accept       json
+// @Param        id path int true "id закладки"
+// @Param        bookmark body BookmarkRecord true "новые значения"
+// @Success      204
+// @Failure      404 {string} string "not found"
+// @Router       /api/bookmarks/{id} [put]
+func (s *apiServer) updateBookmark(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("некорректный id: %w", err))
+		return
+	}
+
+	var record BookmarkRecord
+	if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("ошибка разбора тела запроса: %w", err))
+		return
+	}
+
+	if err := UpdateBookmark(s.db, id, record); err != nil {
+		if errors.Is(err, ErrBookmarkNotFound) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteBookmark godoc
+// @Summary      Удалить закладку (soft delete)
+// @Tags         bookmarks
+// @Param        id path int true "id закладки"
+// @Success      204
+// @Failure      404 {string} string "not found"
+// @Router       /api/bookmarks/{id} [delete]
+func (s *apiServer) deleteBookmark(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("некорректный id: %w", err))
+		return
+	}
+
+	if err := DeleteBookmark(s.db, id); err != nil {
+		if errors.Is(err, ErrBookmarkNotFound) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// searchBookmarks godoc
+// @Summary      Поиск закладок
+// @Tags         bookmarks
+// @Produce      json
+// @Param        q      query string false "подстрока в названии или URL"
+// @Param        tag    query string false "точное совпадение тега"
+// @Param        folder query string false "точное совпадение folder_path"
+// @Param        limit  query int    false "максимум записей (по умолчанию 50)"
+// @Param        offset query int    false "смещение"
+// @Success      200 {array} BookmarkRecord
+// @Router       /api/search [get]
+func (s *apiServer) searchBookmarks(w http.ResponseWriter, r *http.Request) {
+	limit, offset := paginationParams(r)
+
+	records, err := SearchBookmarks(s.db, SearchQuery{
+		Text:   r.URL.Query().Get("q"),
+		Tag:    r.URL.Query().Get("tag"),
+		Folder: r.URL.Query().Get("folder"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, records)
+}
+
+// exportBookmarks godoc
+// @Summary      Экспортировать все закладки
+// @Tags         export
+// @Produce      plain
+// @Param        format query string false "html|json|csv (по умолчанию html)"
+// @Success      200 {string} string "экспортированный файл"
+// @Router       /api/export [get]
+func (s *apiServer) exportBookmarks(w http.ResponseWriter, r *http.Request) {
+	records, err := GetAllBookmarks(s.db)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(records)
+		return
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"id", "name", "url", "folder_path", "tags"})
+		for _, rec := range records {
+			cw.Write([]string{
+				strconv.FormatInt(rec.ID, 10), rec.Name, rec.URL, rec.FolderPath, strings.Join(rec.Tags, ","),
+			})
+		}
+		cw.Flush()
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := ExportNetscapeHTML(w, buildFolderTree(records)); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func paginationParams(r *http.Request) (limit, offset int) {
+	limit = 50
+	offset = 0
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	return limit, offset
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	log.Printf("ошибка API: %v", err)
+	http.Error(w, err.Error(), status)
+}
+
+// runServeCmd обрабатывает подкоманду `serve`: поднимает HTTP API поверх
+// локальной базы закладок.
+func runServeCmd(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "адрес, на котором слушать HTTP")
+	dbPath := fs.String("db", "bookmarks.db", "путь к файлу базы данных SQLite")
+	fs.Parse(args)
+
+	db, err := initDB(*dbPath)
+	if err != nil {
+		log.Fatalf("ошибка инициализации базы данных: %v", err)
+	}
+	defer db.Close()
+
+	// Токен берётся из окружения, а не из флага, чтобы не оставлять
+	// секрет в истории шелла/процессах.
+	token := os.Getenv("BOOKMARK_API_TOKEN")
+	if token == "" {
+		log.Println("предупреждение: BOOKMARK_API_TOKEN не задан, API работает без аутентификации")
+	}
+
+	router := newRouter(db, token)
+
+	fmt.Printf("API закладок слушает на %s\n", *addr)
+	if err := http.ListenAndServe(*addr, router); err != nil {
+		log.Fatalf("ошибка HTTP-сервера: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "api_test.db")
+	db, err := initDB(dbPath)
+	if err != nil {
+		t.Fatalf("ошибка инициализации тестовой базы данных: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// TestAPICreateGetListBookmark проверяет основной CRUD-путь API: создание
+// закладки, получение по id и появление в общем списке.
+func TestAPICreateGetListBookmark(t *testing.T) {
+	db := newTestDB(t)
+	router := newRouter(db, "")
+
+	body := `{"name":"Example","url":"https://example.com","folder_path":"Work","tags":["a","b"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/bookmarks", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/bookmarks: код = %d, тело = %s", rec.Code, rec.Body.String())
+	}
+
+	var created BookmarkRecord
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("ошибка разбора ответа создания: %v", err)
+	}
+	if created.ID == 0 {
+		t.Fatal("ожидался ненулевой id созданной закладки")
+	}
+
+	getReq := httptest.NewRequest(http.MethodGet, "/api/bookmarks/"+strconv.FormatInt(created.ID, 10), nil)
+	getRec := httptest.NewRecorder()
+	router.ServeHTTP(getRec, getReq)
+
+	if getRec.Code != http.StatusOK {
+		t.Fatalf("GET /api/bookmarks/{id}: код = %d, тело = %s", getRec.Code, getRec.Body.String())
+	}
+
+	var fetched BookmarkRecord
+	if err := json.Unmarshal(getRec.Body.Bytes(), &fetched); err != nil {
+		t.Fatalf("ошибка разбора ответа получения: %v", err)
+	}
+	if fetched.Name != "Example" || fetched.FolderPath != "Work" {
+		t.Errorf("неверная закладка получена: %+v", fetched)
+	}
+
+	listReq := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+	listRec := httptest.NewRecorder()
+	router.ServeHTTP(listRec, listReq)
+
+	var list []BookmarkRecord
+	if err := json.Unmarshal(listRec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("ошибка разбора списка закладок: %v", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("ожидалась 1 закладка в списке, получено %d", len(list))
+	}
+}
+
+// TestAPITokenAuth проверяет, что при заданном токене запрос без него
+// отклоняется, а с верным — проходит.
+func TestAPITokenAuth(t *testing.T) {
+	db := newTestDB(t)
+	router := newRouter(db, "secret-token")
+
+	noAuthReq := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+	noAuthRec := httptest.NewRecorder()
+	router.ServeHTTP(noAuthRec, noAuthReq)
+	if noAuthRec.Code != http.StatusUnauthorized {
+		t.Errorf("без токена: код = %d, ожидалось %d", noAuthRec.Code, http.StatusUnauthorized)
+	}
+
+	authReq := httptest.NewRequest(http.MethodGet, "/api/bookmarks", nil)
+	authReq.Header.Set("Authorization", "Bearer secret-token")
+	authRec := httptest.NewRecorder()
+	router.ServeHTTP(authRec, authReq)
+	if authRec.Code != http.StatusOK {
+		t.Errorf("с верным токеном: код = %d, ожидалось %d", authRec.Code, http.StatusOK)
+	}
+}
+
+// TestSearchBookmarksFilters проверяет фильтрацию SearchBookmarks по тегу и
+// по подстроке в названии.
+func TestSearchBookmarksFilters(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := CreateBookmark(db, BookmarkRecord{Name: "Go docs", URL: "https://go.dev", Tags: Tags{"dev"}}); err != nil {
+		t.Fatalf("ошибка создания закладки: %v", err)
+	}
+	if _, err := CreateBookmark(db, BookmarkRecord{Name: "Cooking blog", URL: "https://cooking.example.com", Tags: Tags{"food"}}); err != nil {
+		t.Fatalf("ошибка создания закладки: %v", err)
+	}
+
+	byTag, err := SearchBookmarks(db, SearchQuery{Tag: "dev"})
+	if err != nil {
+		t.Fatalf("ошибка поиска по тегу: %v", err)
+	}
+	if len(byTag) != 1 || byTag[0].Name != "Go docs" {
+		t.Errorf("неверный результат поиска по тегу dev: %+v", byTag)
+	}
+
+	byText, err := SearchBookmarks(db, SearchQuery{Text: "cooking"})
+	if err != nil {
+		t.Fatalf("ошибка поиска по тексту: %v", err)
+	}
+	if len(byText) != 1 || byText[0].Name != "Cooking blog" {
+		t.Errorf("неверный результат поиска по тексту cooking: %+v", byText)
+	}
+
+	byWildcardTag, err := SearchBookmarks(db, SearchQuery{Tag: "%"})
+	if err != nil {
+		t.Fatalf("ошибка поиска по тегу %%: %v", err)
+	}
+	if len(byWildcardTag) != 0 {
+		t.Errorf("тег %%q не должен трактоваться как шаблон LIKE, получено %d совпадений", len(byWildcardTag))
+	}
+}
+
+// TestUpdateDeleteBookmarkNotFound проверяет, что UpdateBookmark/DeleteBookmark
+// возвращают ErrBookmarkNotFound для несуществующего id, а соответствующие
+// HTTP-обработчики отвечают 404 вместо лживого успеха.
+func TestUpdateDeleteBookmarkNotFound(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := UpdateBookmark(db, 9999, BookmarkRecord{Name: "X", URL: "https://x.example.com"}); !errors.Is(err, ErrBookmarkNotFound) {
+		t.Errorf("UpdateBookmark для несуществующего id: ошибка = %v, ожидалось ErrBookmarkNotFound", err)
+	}
+	if err := DeleteBookmark(db, 9999); !errors.Is(err, ErrBookmarkNotFound) {
+		t.Errorf("DeleteBookmark для несуществующего id: ошибка = %v, ожидалось ErrBookmarkNotFound", err)
+	}
+
+	router := newRouter(db, "")
+
+	putReq := httptest.NewRequest(http.MethodPut, "/api/bookmarks/9999", strings.NewReader(`{"name":"X","url":"https://x.example.com"}`))
+	putRec := httptest.NewRecorder()
+	router.ServeHTTP(putRec, putReq)
+	if putRec.Code != http.StatusNotFound {
+		t.Errorf("PUT /api/bookmarks/9999: код = %d, ожидалось %d", putRec.Code, http.StatusNotFound)
+	}
+
+	delReq := httptest.NewRequest(http.MethodDelete, "/api/bookmarks/9999", nil)
+	delRec := httptest.NewRecorder()
+	router.ServeHTTP(delRec, delReq)
+	if delRec.Code != http.StatusNotFound {
+		t.Errorf("DELETE /api/bookmarks/9999: код = %d, ожидалось %d", delRec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce — время, в течение которого подряд идущие события файловой
+// системы схлопываются в одну обработку. Chrome переписывает Bookmarks
+// несколькими операциями (временный файл + rename), поэтому обработка
+// "в лоб" на каждое событие привела бы к частичным/мусорным прочтениям.
+const watchDebounce = 500 * time.Millisecond
+
+// ChangeKind описывает природу изменения закладки, обнаруженного Watcher.
+type ChangeKind int
+
+const (
+	BookmarkAdded ChangeKind = iota
+	BookmarkModified
+	BookmarkRemoved
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case BookmarkAdded:
+		return "added"
+	case BookmarkModified:
+		return "modified"
+	case BookmarkRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// BookmarkChange — одно изменение, отданное в канал Watcher.Changes. Old
+// заполнен для modified/removed, New — для added/modified.
+type BookmarkChange struct {
+	Kind ChangeKind
+	Old  *BookmarkItem
+	New  *BookmarkItem
+}
+
+// Watcher следит за файлом закладок Chrome (и совместимых браузеров на
+// Chromium) и сообщает об изменениях через канал Changes.
+type Watcher struct {
+	path string
+	fsw  *fsnotify.Watcher
+
+	changes chan BookmarkChange
+	done    chan struct{}
+
+	lastChecksum string
+	lastItems    map[string]BookmarkItem
+}
+
+// NewWatcher начинает наблюдение за каталогом, содержащим path, и
+// запоминает текущее содержимое файла закладок, чтобы не прислать ложные
+// события "добавлено" для уже существующих закладок при старте.
+func NewWatcher(path string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания fsnotify watcher: %w", err)
+	}
+
+	// Watcher следит за каталогом, а не за самим файлом: Chrome пишет
+	// новую версию во временный файл и атомарно переименовывает его,
+	// из-за чего инод исходного файла меняется и прямое наблюдение за
+	// ним теряет события после первого rename.
+	dir := filepath.Dir(path)
+	if err := fsw.Add(dir); err != nil {
+		fsw.Close()
+		return nil, fmt.Errorf("ошибка добавления каталога %s под наблюдение: %w", dir, err)
+	}
+
+	w := &Watcher{
+		path:    path,
+		fsw:     fsw,
+		changes: make(chan BookmarkChange),
+		done:    make(chan struct{}),
+	}
+
+	if checksum, items, err := reload(path, "", nil, nil); err == nil {
+		w.lastChecksum = checksum
+		w.lastItems = items
+	} else {
+		log.Printf("не удалось прочитать начальное состояние закладок: %v", err)
+	}
+
+	go w.loop()
+
+	return w, nil
+}
+
+// Changes возвращает канал, в который Watcher пишет обнаруженные изменения.
+func (w *Watcher) Changes() <-chan BookmarkChange {
+	return w.changes
+}
+
+// Close останавливает наблюдение и закрывает канал Changes.
+func (w *Watcher) Close() error {
+	close(w.done)
+	err := w.fsw.Close()
+	return err
+}
+
+func (w *Watcher) loop() {
+	defer close(w.changes)
+
+	var timer *time.Timer
+	var fireCh <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			// Интересуют только события по имени самого файла
+			// закладок (Chrome также пишет рядом Bookmarks.bak).
+			if filepath.Base(event.Name) != filepath.Base(w.path) {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+
+			if timer == nil {
+				timer = time.NewTimer(watchDebounce)
+			} else {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(watchDebounce)
+			}
+			fireCh = timer.C
+
+		case <-fireCh:
+			fireCh = nil
+			if err := w.commit(); err != nil {
+				log.Printf("ошибка обработки изменений закладок: %v", err)
+			}
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("ошибка наблюдения за файлом закладок: %v", err)
+
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// commit перечитывает файл закладок и публикует обнаруженные изменения.
+func (w *Watcher) commit() error {
+	checksum, items, err := reload(w.path, w.lastChecksum, w.lastItems, w.changes)
+	if err != nil {
+		return err
+	}
+
+	w.lastChecksum = checksum
+	w.lastItems = items
+
+	return nil
+}
+
+// reload парсит файл закладок по path, проверяет поле checksum (пустое
+// значение означает, что файл ещё не дописан или повреждён, и такое
+// чтение отбрасывается) и, если содержимое изменилось, публикует разницу
+// с lastItems в changes. Если changes == nil, разница не публикуется —
+// это используется при первом чтении, чтобы не присылать "добавлено" для
+// уже существующих закладок.
+func reload(path string, lastChecksum string, lastItems map[string]BookmarkItem, changes chan<- BookmarkChange) (string, map[string]BookmarkItem, error) {
+	bookmarks, err := parseBookmarks(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("ошибка парсинга закладок: %w", err)
+	}
+	if bookmarks.Checksum == "" {
+		return "", nil, fmt.Errorf("файл закладок без поля checksum, похоже он ещё не дописан")
+	}
+	if bookmarks.Checksum == lastChecksum {
+		return lastChecksum, lastItems, nil
+	}
+
+	var flat []BookmarkItem
+	flat = append(flat, extractBookmarks(bookmarks.Roots.BookmarkBar.Children)...)
+	flat = append(flat, extractBookmarks(bookmarks.Roots.Other.Children)...)
+	flat = append(flat, extractBookmarks(bookmarks.Roots.Synced.Children)...)
+
+	current := make(map[string]BookmarkItem, len(flat))
+	for _, item := range flat {
+		current[item.URL] = item
+	}
+
+	if changes != nil {
+		for url, item := range current {
+			item := item
+			if old, ok := lastItems[url]; !ok {
+				changes <- BookmarkChange{Kind: BookmarkAdded, New: &item}
+			} else if old.Name != item.Name || old.FolderPath != item.FolderPath {
+				old := old
+				changes <- BookmarkChange{Kind: BookmarkModified, Old: &old, New: &item}
+			}
+		}
+		for url, old := range lastItems {
+			old := old
+			if _, ok := current[url]; !ok {
+				changes <- BookmarkChange{Kind: BookmarkRemoved, Old: &old}
+			}
+		}
+	}
+
+	return bookmarks.Checksum, current, nil
+}
+
+// runWatchCmd обрабатывает подкоманду `watch`: следит за файлом закладок
+// выбранного браузера и после каждого изменения повторяет пайплайн
+// разбора + сохранения в SQLite.
+func runWatchCmd(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	browser := fs.String("browser", "chrome", "источник закладок: chrome, chromium, edge, brave, vivaldi")
+	profile := fs.String("profile", "", "имя профиля браузера (по умолчанию — профиль по умолчанию)")
+	dbPath := fs.String("db", "bookmarks.db", "путь к файлу базы данных SQLite")
+	fs.Parse(args)
+
+	source, err := getBrowserSource(*browser)
+	if err != nil {
+		log.Fatalf("Ошибка: %v", err)
+	}
+
+	path, err := source.ResolvePath(*profile)
+	if err != nil {
+		log.Fatalf("Ошибка: %v", err)
+	}
+
+	db, err := initDB(*dbPath)
+	if err != nil {
+		log.Fatalf("ошибка инициализации базы данных: %v", err)
+	}
+	defer db.Close()
+
+	watcher, err := NewWatcher(path)
+	if err != nil {
+		log.Fatalf("ошибка запуска наблюдения: %v", err)
+	}
+	defer watcher.Close()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	fmt.Printf("Наблюдаю за %s (Ctrl+C для остановки)...\n", path)
+
+	for {
+		select {
+		case change, ok := <-watcher.Changes():
+			if !ok {
+				return
+			}
+
+			var url string
+			if change.New != nil {
+				url = change.New.URL
+			} else if change.Old != nil {
+				url = change.Old.URL
+			}
+			fmt.Printf("[%s] %s\n", change.Kind, url)
+
+			items, err := source.Load(path)
+			if err != nil {
+				log.Printf("ошибка перечитывания закладок: %v", err)
+				continue
+			}
+			if err := saveBookmarksToDB(db, extractBookmarks(items)); err != nil {
+				log.Printf("ошибка сохранения закладок: %v", err)
+			}
+
+		case <-sigCh:
+			fmt.Println("Остановка наблюдения...")
+			return
+		}
+	}
+}
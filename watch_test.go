@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeChromeBookmarksFile(t *testing.T, path, checksum string, urls []BookmarkItem) {
+	t.Helper()
+
+	bookmarks := Bookmarks{
+		Version:  1,
+		Checksum: checksum,
+		Roots: Roots{
+			BookmarkBar: BookmarkFolder{
+				Type:     "folder",
+				Name:     "Bookmarks bar",
+				Children: urls,
+			},
+		},
+	}
+
+	data, err := json.Marshal(bookmarks)
+	if err != nil {
+		t.Fatalf("ошибка сериализации тестового файла закладок: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("ошибка записи тестового файла закладок: %v", err)
+	}
+}
+
+// TestReloadDetectsAddedModifiedRemoved проверяет, что reload() верно
+// публикует added/modified/removed при изменении файла закладок (отказ
+// для файла с пустым checksum проверяет TestReloadRejectsMissingChecksum).
+func TestReloadDetectsAddedModifiedRemoved(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Bookmarks")
+
+	writeChromeBookmarksFile(t, path, "checksum-1", []BookmarkItem{
+		{Type: "url", Name: "Kept", URL: "https://kept.example.com"},
+		{Type: "url", Name: "To Remove", URL: "https://removed.example.com"},
+	})
+
+	checksum, items, err := reload(path, "", nil, nil)
+	if err != nil {
+		t.Fatalf("ошибка первого чтения: %v", err)
+	}
+	if checksum != "checksum-1" {
+		t.Fatalf("неверный checksum после первого чтения: %q", checksum)
+	}
+	if len(items) != 2 {
+		t.Fatalf("ожидалось 2 закладки, получено %d", len(items))
+	}
+
+	writeChromeBookmarksFile(t, path, "checksum-2", []BookmarkItem{
+		{Type: "url", Name: "Kept renamed", URL: "https://kept.example.com"},
+		{Type: "url", Name: "New", URL: "https://new.example.com"},
+	})
+
+	changes := make(chan BookmarkChange, 10)
+	newChecksum, newItems, err := reload(path, checksum, items, changes)
+	close(changes)
+	if err != nil {
+		t.Fatalf("ошибка второго чтения: %v", err)
+	}
+	if newChecksum != "checksum-2" {
+		t.Fatalf("неверный checksum после второго чтения: %q", newChecksum)
+	}
+	if len(newItems) != 2 {
+		t.Fatalf("ожидалось 2 закладки после обновления, получено %d", len(newItems))
+	}
+
+	var added, modified, removed int
+	for c := range changes {
+		switch c.Kind {
+		case BookmarkAdded:
+			added++
+		case BookmarkModified:
+			modified++
+		case BookmarkRemoved:
+			removed++
+		}
+	}
+
+	if added != 1 {
+		t.Errorf("added = %d, ожидалось 1", added)
+	}
+	if modified != 1 {
+		t.Errorf("modified = %d, ожидалось 1", modified)
+	}
+	if removed != 1 {
+		t.Errorf("removed = %d, ожидалось 1", removed)
+	}
+}
+
+// TestReloadRejectsMissingChecksum проверяет, что файл без поля checksum
+// (похоже на недописанный Chrome'ом файл) отбрасывается с ошибкой.
+func TestReloadRejectsMissingChecksum(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Bookmarks")
+
+	writeChromeBookmarksFile(t, path, "", []BookmarkItem{
+		{Type: "url", Name: "Example", URL: "https://example.com"},
+	})
+
+	if _, _, err := reload(path, "", nil, nil); err == nil {
+		t.Error("ожидалась ошибка для файла без checksum, получен nil")
+	}
+}